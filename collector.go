@@ -0,0 +1,49 @@
+package main
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// deviceLabels is the label set every per-device metric carries. account
+// identifies which configured YoLink account/home the device belongs to.
+var deviceLabels = []string{"account", "device_id", "device_name", "model"}
+
+// Collector describes and emits the metrics for one YoLink device type.
+// Modeled on mikrotik-exporter's per-device-type deviceCollector pattern:
+// each implementation owns its own prometheus.Descs and only knows how to
+// read the fields of DeviceStateResponse that its device type populates.
+type Collector interface {
+	// DeviceType is the YoLink "type" field this collector handles, e.g. "THSensor".
+	DeviceType() string
+	Describe(ch chan<- *prometheus.Desc)
+	Collect(ch chan<- prometheus.Metric, account string, device Device, state *DeviceStateResponse)
+}
+
+// NewCollector builds the Collector for a name as used in the
+// `collectors` config list (e.g. "thsensor"). It returns nil for unknown
+// names so callers can skip and warn instead of failing to start.
+func NewCollector(name string) Collector {
+	switch name {
+	case "thsensor":
+		return newTHSensorCollector()
+	case "leaksensor":
+		return newLeakSensorCollector()
+	case "motionsensor":
+		return newMotionSensorCollector()
+	case "doorsensor":
+		return newDoorSensorCollector()
+	case "outlet":
+		return newOutletCollector()
+	case "hub":
+		return newHubCollector()
+	default:
+		return nil
+	}
+}
+
+func newSignalDesc() *prometheus.Desc {
+	return prometheus.NewDesc(
+		"yolink_signal_rssi",
+		"Device LoRa signal strength in dBm",
+		deviceLabels,
+		nil,
+	)
+}
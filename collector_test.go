@@ -0,0 +1,125 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// collectValues drains a Collector.Collect call into the raw gauge values,
+// in emission order, so tests can assert on what actually went out without
+// caring about each Desc's fqName.
+func collectValues(t *testing.T, collector Collector, device Device, state *DeviceStateResponse) []float64 {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric, 8)
+	collector.Collect(ch, "test-account", device, state)
+	close(ch)
+
+	var values []float64
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("failed to write metric: %v", err)
+		}
+		values = append(values, metric.GetGauge().GetValue())
+	}
+	return values
+}
+
+func stateWithReading(reading string, battery, signal int) *DeviceStateResponse {
+	s := &DeviceStateResponse{}
+	s.Data.State.State = reading
+	s.Data.State.Battery = battery
+	s.Data.LoRaInfo.Signal = signal
+	return s
+}
+
+func TestTHSensorCollectorCollect(t *testing.T) {
+	state := &DeviceStateResponse{}
+	state.Data.State.Temperature = 21.5
+	state.Data.State.Humidity = 47.0
+	state.Data.State.Battery = 3
+	state.Data.LoRaInfo.Signal = -65
+
+	values := collectValues(t, newTHSensorCollector(), Device{DeviceID: "dev-1"}, state)
+	want := []float64{21.5, 47.0, 3, -65} // temperature, humidity, battery, signal
+	if len(values) != len(want) {
+		t.Fatalf("got %d metrics, want %d: %v", len(values), len(want), values)
+	}
+	for i, v := range want {
+		if values[i] != v {
+			t.Errorf("metric[%d] = %v, want %v", i, values[i], v)
+		}
+	}
+}
+
+func TestBoolCollectorsCollect(t *testing.T) {
+	cases := []struct {
+		name      string
+		collector Collector
+		reading   string
+		want      float64 // the device-specific boolean metric, always first
+	}{
+		{"leak sensor alert", newLeakSensorCollector(), "alert", 1},
+		{"leak sensor normal", newLeakSensorCollector(), "normal", 0},
+		{"motion sensor alert", newMotionSensorCollector(), "alert", 1},
+		{"motion sensor normal", newMotionSensorCollector(), "normal", 0},
+		{"door sensor open", newDoorSensorCollector(), "open", 1},
+		{"door sensor closed", newDoorSensorCollector(), "closed", 0},
+		{"outlet open", newOutletCollector(), "open", 1},
+		{"outlet closed", newOutletCollector(), "closed", 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := stateWithReading(tc.reading, 2, -70)
+			values := collectValues(t, tc.collector, Device{DeviceID: "dev-1"}, state)
+			if len(values) == 0 {
+				t.Fatal("expected at least one metric")
+			}
+			if values[0] != tc.want {
+				t.Errorf("%s: got %v, want %v", tc.collector.DeviceType(), values[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestHubCollectorCollect(t *testing.T) {
+	state := &DeviceStateResponse{}
+	state.Data.LoRaInfo.Signal = -80
+
+	values := collectValues(t, newHubCollector(), Device{DeviceID: "dev-1"}, state)
+	if len(values) != 1 || values[0] != -80 {
+		t.Errorf("got %v, want [-80]", values)
+	}
+}
+
+func TestNewCollectorKnownTypes(t *testing.T) {
+	cases := map[string]string{
+		"thsensor":     "THSensor",
+		"leaksensor":   "LeakSensor",
+		"motionsensor": "MotionSensor",
+		"doorsensor":   "DoorSensor",
+		"outlet":       "Outlet",
+		"hub":          "Hub",
+	}
+
+	for name, wantType := range cases {
+		c := NewCollector(name)
+		if c == nil {
+			t.Errorf("NewCollector(%q) returned nil, expected a collector", name)
+			continue
+		}
+		if c.DeviceType() != wantType {
+			t.Errorf("NewCollector(%q).DeviceType() = %q, want %q", name, c.DeviceType(), wantType)
+		}
+	}
+}
+
+func TestNewCollectorUnknownType(t *testing.T) {
+	if c := NewCollector("notarealdevice"); c != nil {
+		t.Errorf("NewCollector(%q) = %v, want nil", "notarealdevice", c)
+	}
+}
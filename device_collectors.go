@@ -0,0 +1,178 @@
+package main
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type THSensorCollector struct {
+	temperature *prometheus.Desc
+	humidity    *prometheus.Desc
+	battery     *prometheus.Desc
+	signal      *prometheus.Desc
+}
+
+func newTHSensorCollector() *THSensorCollector {
+	return &THSensorCollector{
+		temperature: prometheus.NewDesc("yolink_temperature_celsius", "Temperature in Celsius", deviceLabels, nil),
+		humidity:    prometheus.NewDesc("yolink_humidity_percent", "Humidity percentage", deviceLabels, nil),
+		battery:     prometheus.NewDesc("yolink_battery_level", "Battery level (1-4)", deviceLabels, nil),
+		signal:      newSignalDesc(),
+	}
+}
+
+func (c *THSensorCollector) DeviceType() string { return "THSensor" }
+
+func (c *THSensorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.temperature
+	ch <- c.humidity
+	ch <- c.battery
+	ch <- c.signal
+}
+
+func (c *THSensorCollector) Collect(ch chan<- prometheus.Metric, account string, device Device, state *DeviceStateResponse) {
+	labels := []string{account, device.DeviceID, device.Name, device.ModelName}
+	ch <- prometheus.MustNewConstMetric(c.temperature, prometheus.GaugeValue, state.Data.State.Temperature, labels...)
+	ch <- prometheus.MustNewConstMetric(c.humidity, prometheus.GaugeValue, state.Data.State.Humidity, labels...)
+	ch <- prometheus.MustNewConstMetric(c.battery, prometheus.GaugeValue, float64(state.Data.State.Battery), labels...)
+	ch <- prometheus.MustNewConstMetric(c.signal, prometheus.GaugeValue, float64(state.Data.LoRaInfo.Signal), labels...)
+}
+
+type LeakSensorCollector struct {
+	leakDetected *prometheus.Desc
+	battery      *prometheus.Desc
+	signal       *prometheus.Desc
+}
+
+func newLeakSensorCollector() *LeakSensorCollector {
+	return &LeakSensorCollector{
+		leakDetected: prometheus.NewDesc("yolink_leak_detected", "Leak detected (1=alert, 0=normal)", deviceLabels, nil),
+		battery:      prometheus.NewDesc("yolink_battery_level", "Battery level (1-4)", deviceLabels, nil),
+		signal:       newSignalDesc(),
+	}
+}
+
+func (c *LeakSensorCollector) DeviceType() string { return "LeakSensor" }
+
+func (c *LeakSensorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.leakDetected
+	ch <- c.battery
+	ch <- c.signal
+}
+
+func (c *LeakSensorCollector) Collect(ch chan<- prometheus.Metric, account string, device Device, state *DeviceStateResponse) {
+	labels := []string{account, device.DeviceID, device.Name, device.ModelName}
+	ch <- prometheus.MustNewConstMetric(c.leakDetected, prometheus.GaugeValue, boolMetric(state.Data.State.State == "alert"), labels...)
+	ch <- prometheus.MustNewConstMetric(c.battery, prometheus.GaugeValue, float64(state.Data.State.Battery), labels...)
+	ch <- prometheus.MustNewConstMetric(c.signal, prometheus.GaugeValue, float64(state.Data.LoRaInfo.Signal), labels...)
+}
+
+type MotionSensorCollector struct {
+	motionDetected *prometheus.Desc
+	battery        *prometheus.Desc
+	signal         *prometheus.Desc
+}
+
+func newMotionSensorCollector() *MotionSensorCollector {
+	return &MotionSensorCollector{
+		motionDetected: prometheus.NewDesc("yolink_motion_detected", "Motion detected (1=alert, 0=normal)", deviceLabels, nil),
+		battery:        prometheus.NewDesc("yolink_battery_level", "Battery level (1-4)", deviceLabels, nil),
+		signal:         newSignalDesc(),
+	}
+}
+
+func (c *MotionSensorCollector) DeviceType() string { return "MotionSensor" }
+
+func (c *MotionSensorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.motionDetected
+	ch <- c.battery
+	ch <- c.signal
+}
+
+func (c *MotionSensorCollector) Collect(ch chan<- prometheus.Metric, account string, device Device, state *DeviceStateResponse) {
+	labels := []string{account, device.DeviceID, device.Name, device.ModelName}
+	ch <- prometheus.MustNewConstMetric(c.motionDetected, prometheus.GaugeValue, boolMetric(state.Data.State.State == "alert"), labels...)
+	ch <- prometheus.MustNewConstMetric(c.battery, prometheus.GaugeValue, float64(state.Data.State.Battery), labels...)
+	ch <- prometheus.MustNewConstMetric(c.signal, prometheus.GaugeValue, float64(state.Data.LoRaInfo.Signal), labels...)
+}
+
+type DoorSensorCollector struct {
+	doorOpen *prometheus.Desc
+	battery  *prometheus.Desc
+	signal   *prometheus.Desc
+}
+
+func newDoorSensorCollector() *DoorSensorCollector {
+	return &DoorSensorCollector{
+		doorOpen: prometheus.NewDesc("yolink_door_open", "Door open status (1=open, 0=closed)", deviceLabels, nil),
+		battery:  prometheus.NewDesc("yolink_battery_level", "Battery level (1-4)", deviceLabels, nil),
+		signal:   newSignalDesc(),
+	}
+}
+
+func (c *DoorSensorCollector) DeviceType() string { return "DoorSensor" }
+
+func (c *DoorSensorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.doorOpen
+	ch <- c.battery
+	ch <- c.signal
+}
+
+func (c *DoorSensorCollector) Collect(ch chan<- prometheus.Metric, account string, device Device, state *DeviceStateResponse) {
+	labels := []string{account, device.DeviceID, device.Name, device.ModelName}
+	ch <- prometheus.MustNewConstMetric(c.doorOpen, prometheus.GaugeValue, boolMetric(state.Data.State.State == "open"), labels...)
+	ch <- prometheus.MustNewConstMetric(c.battery, prometheus.GaugeValue, float64(state.Data.State.Battery), labels...)
+	ch <- prometheus.MustNewConstMetric(c.signal, prometheus.GaugeValue, float64(state.Data.LoRaInfo.Signal), labels...)
+}
+
+type OutletCollector struct {
+	outletState *prometheus.Desc
+	signal      *prometheus.Desc
+}
+
+func newOutletCollector() *OutletCollector {
+	return &OutletCollector{
+		outletState: prometheus.NewDesc("yolink_outlet_state", "Outlet relay state (1=on, 0=off)", deviceLabels, nil),
+		signal:      newSignalDesc(),
+	}
+}
+
+func (c *OutletCollector) DeviceType() string { return "Outlet" }
+
+func (c *OutletCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.outletState
+	ch <- c.signal
+}
+
+func (c *OutletCollector) Collect(ch chan<- prometheus.Metric, account string, device Device, state *DeviceStateResponse) {
+	labels := []string{account, device.DeviceID, device.Name, device.ModelName}
+	ch <- prometheus.MustNewConstMetric(c.outletState, prometheus.GaugeValue, boolMetric(state.Data.State.State == "open"), labels...)
+	ch <- prometheus.MustNewConstMetric(c.signal, prometheus.GaugeValue, float64(state.Data.LoRaInfo.Signal), labels...)
+}
+
+// HubCollector only reports signal strength; a hub has no battery and no
+// sensor state of its own.
+type HubCollector struct {
+	signal *prometheus.Desc
+}
+
+func newHubCollector() *HubCollector {
+	return &HubCollector{
+		signal: newSignalDesc(),
+	}
+}
+
+func (c *HubCollector) DeviceType() string { return "Hub" }
+
+func (c *HubCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.signal
+}
+
+func (c *HubCollector) Collect(ch chan<- prometheus.Metric, account string, device Device, state *DeviceStateResponse) {
+	labels := []string{account, device.DeviceID, device.Name, device.ModelName}
+	ch <- prometheus.MustNewConstMetric(c.signal, prometheus.GaugeValue, float64(state.Data.LoRaInfo.Signal), labels...)
+}
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1.0
+	}
+	return 0.0
+}
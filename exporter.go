@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"sync"
 	"time"
@@ -9,55 +10,86 @@ import (
 	"github.com/spf13/viper"
 )
 
+// AccountClient pairs a YoLink account's REST client with its optional
+// MQTT source, so the exporter can fan out across multiple YoLink
+// homes/hubs from a single process.
+type AccountClient struct {
+	Name       string
+	Client     *YoLinkClient
+	MQTTSource *MQTTSource
+}
+
+// Close tears down this account's MQTT connection, if any. Call it on an
+// AccountClient that's being replaced or discarded so reload doesn't leak
+// a broker connection and its background goroutines.
+func (a AccountClient) Close() {
+	if a.MQTTSource != nil {
+		a.MQTTSource.Stop()
+	}
+}
+
+// closeAccountClients closes every account in the slice, e.g. when
+// discarding a partially-built list after a later account fails to set up.
+func closeAccountClients(accounts []AccountClient) {
+	for _, acct := range accounts {
+		acct.Close()
+	}
+}
+
 type YoLinkExporter struct {
-	client *YoLinkClient
-	mutex  sync.RWMutex
+	accounts []AccountClient
+	mutex    sync.RWMutex
+
+	// collectors is keyed by YoLink device type (e.g. "THSensor"), built
+	// from the enabled names in the `collectors` config list.
+	collectors map[string]Collector
 
-	// Metrics
-	temperature *prometheus.Desc
-	humidity    *prometheus.Desc
-	battery     *prometheus.Desc
+	// Metrics common to every device type
 	online      *prometheus.Desc
 	lastUpdated *prometheus.Desc
 	up          *prometheus.Desc
 
-	// Cache
+	// Cache, keyed by account name
 	lastScrape   time.Time
-	devices      []Device
-	deviceStates map[string]*DeviceStateResponse
+	devices      map[string][]Device
+	deviceStates map[string]map[string]*DeviceStateResponse
+}
+
+// collectorsFromConfig builds the device-type -> Collector map from
+// viper's `collectors` list, defaulting to thsensor alone when unset.
+// Unknown names are logged and skipped rather than failing startup.
+func collectorsFromConfig() map[string]Collector {
+	collectors := make(map[string]Collector)
+	for _, name := range viper.GetStringSlice("collectors") {
+		collector := NewCollector(name)
+		if collector == nil {
+			log.Printf("Unknown collector %q in config, skipping", name)
+			continue
+		}
+		collectors[collector.DeviceType()] = collector
+	}
+	if len(collectors) == 0 {
+		collectors[newTHSensorCollector().DeviceType()] = newTHSensorCollector()
+	}
+	return collectors
 }
 
-func NewYoLinkExporter(client *YoLinkClient) *YoLinkExporter {
+// NewYoLinkExporter builds an exporter with a Collector registered for
+// each name in viper's `collectors` list.
+func NewYoLinkExporter(accounts []AccountClient) *YoLinkExporter {
 	return &YoLinkExporter{
-		client: client,
-		temperature: prometheus.NewDesc(
-			"yolink_temperature_celsius",
-			"Temperature in Celsius",
-			[]string{"device_id", "device_name", "model"},
-			nil,
-		),
-		humidity: prometheus.NewDesc(
-			"yolink_humidity_percent",
-			"Humidity percentage",
-			[]string{"device_id", "device_name", "model"},
-			nil,
-		),
-		battery: prometheus.NewDesc(
-			"yolink_battery_level",
-			"Battery level (1-4)",
-			[]string{"device_id", "device_name", "model"},
-			nil,
-		),
+		accounts:   accounts,
+		collectors: collectorsFromConfig(),
 		online: prometheus.NewDesc(
 			"yolink_device_online",
 			"Device online status (1=online, 0=offline)",
-			[]string{"device_id", "device_name", "model"},
+			deviceLabels,
 			nil,
 		),
 		lastUpdated: prometheus.NewDesc(
 			"yolink_last_updated_timestamp",
 			"Unix timestamp of when the device last reported data",
-			[]string{"device_id", "device_name", "model"},
+			deviceLabels,
 			nil,
 		),
 		up: prometheus.NewDesc(
@@ -66,17 +98,35 @@ func NewYoLinkExporter(client *YoLinkClient) *YoLinkExporter {
 			nil,
 			nil,
 		),
-		deviceStates: make(map[string]*DeviceStateResponse),
+		devices:      make(map[string][]Device),
+		deviceStates: make(map[string]map[string]*DeviceStateResponse),
 	}
 }
 
+// Reconfigure swaps in newly built account clients and collectors, e.g.
+// after a config reload, and clears the cache so the next Collect fetches
+// fresh data under the new configuration. The outgoing accounts are closed
+// after the swap so their MQTT connections don't leak.
+func (e *YoLinkExporter) Reconfigure(accounts []AccountClient) {
+	e.mutex.Lock()
+	old := e.accounts
+	e.accounts = accounts
+	e.collectors = collectorsFromConfig()
+	e.devices = make(map[string][]Device)
+	e.deviceStates = make(map[string]map[string]*DeviceStateResponse)
+	e.lastScrape = time.Time{}
+	e.mutex.Unlock()
+
+	closeAccountClients(old)
+}
+
 func (e *YoLinkExporter) Describe(ch chan<- *prometheus.Desc) {
-	ch <- e.temperature
-	ch <- e.humidity
-	ch <- e.battery
 	ch <- e.online
 	ch <- e.lastUpdated
 	ch <- e.up
+	for _, collector := range e.collectors {
+		collector.Describe(ch)
+	}
 }
 
 func (e *YoLinkExporter) Collect(ch chan<- prometheus.Metric) {
@@ -97,62 +147,146 @@ func (e *YoLinkExporter) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(e.up, prometheus.GaugeValue, 1)
 
 	// Export device metrics
-	for _, device := range e.devices {
-		state, exists := e.deviceStates[device.DeviceID]
-		if !exists {
-			continue
-		}
+	for _, acct := range e.accounts {
+		for _, device := range e.devices[acct.Name] {
+			collector, enabled := e.collectors[device.Type]
+			if !enabled {
+				continue
+			}
 
-		labels := []string{device.DeviceID, device.Name, device.ModelName}
+			state, exists := e.deviceStates[acct.Name][device.DeviceID]
+			if !exists {
+				continue
+			}
 
-		// Online status
-		onlineValue := 0.0
-		if state.Data.Online {
-			onlineValue = 1.0
-		}
-		ch <- prometheus.MustNewConstMetric(e.online, prometheus.GaugeValue, onlineValue, labels...)
-
-		// Last updated timestamp
-		if reportAt, err := time.Parse(time.RFC3339, state.Data.ReportAt); err == nil {
-			lastUpdatedValue := float64(reportAt.Unix())
-			ch <- prometheus.MustNewConstMetric(e.lastUpdated, prometheus.GaugeValue, lastUpdatedValue, labels...)
-		} else {
-			log.Printf("Failed to parse reportAt time for device %s: %v", device.DeviceID, err)
-		}
+			labels := []string{acct.Name, device.DeviceID, device.Name, device.ModelName}
 
-		// Only export sensor data if device is online
-		if state.Data.Online {
-			// Temperature
-			ch <- prometheus.MustNewConstMetric(e.temperature, prometheus.GaugeValue, state.Data.State.Temperature, labels...)
+			// Online status
+			onlineValue := 0.0
+			if state.Data.Online {
+				onlineValue = 1.0
+			}
+			ch <- prometheus.MustNewConstMetric(e.online, prometheus.GaugeValue, onlineValue, labels...)
 
-			// Humidity
-			ch <- prometheus.MustNewConstMetric(e.humidity, prometheus.GaugeValue, state.Data.State.Humidity, labels...)
+			// Last updated timestamp
+			if reportAt, err := time.Parse(time.RFC3339, state.Data.ReportAt); err == nil {
+				lastUpdatedValue := float64(reportAt.Unix())
+				ch <- prometheus.MustNewConstMetric(e.lastUpdated, prometheus.GaugeValue, lastUpdatedValue, labels...)
+			} else {
+				log.Printf("Failed to parse reportAt time for device %s (account %s): %v", device.DeviceID, acct.Name, err)
+			}
 
-			// Battery level
-			ch <- prometheus.MustNewConstMetric(e.battery, prometheus.GaugeValue, float64(state.Data.State.Battery), labels...)
+			// Only export sensor data if device is online
+			if state.Data.Online {
+				collector.Collect(ch, acct.Name, device, state)
+			}
 		}
 	}
 }
 
+// refreshData fans the REST/MQTT refresh out across every configured
+// account. One account's failure doesn't block the others from reporting;
+// refreshData only fails outright if every account failed.
 func (e *YoLinkExporter) refreshData() error {
-	// Get device list
-	devices, err := e.client.GetDevices()
-	if err != nil {
-		return err
+	type accountResult struct {
+		devices []Device
+		states  map[string]*DeviceStateResponse
+		err     error
 	}
 
-	e.devices = devices
-	e.deviceStates = make(map[string]*DeviceStateResponse)
+	results := make([]accountResult, len(e.accounts))
+	var wg sync.WaitGroup
+	for i, acct := range e.accounts {
+		wg.Add(1)
+		go func(i int, acct AccountClient) {
+			defer wg.Done()
+			devices, states, err := e.refreshAccount(acct)
+			results[i] = accountResult{devices: devices, states: states, err: err}
+		}(i, acct)
+	}
+	wg.Wait()
 
-	// Get state for each device
-	for _, device := range devices {
-		state, err := e.client.GetDeviceState(device)
-		if err != nil {
-			log.Printf("Failed to get state for device %s (%s): %v", device.Name, device.DeviceID, err)
+	devices := make(map[string][]Device, len(e.accounts))
+	deviceStates := make(map[string]map[string]*DeviceStateResponse, len(e.accounts))
+
+	var firstErr error
+	for i, acct := range e.accounts {
+		result := results[i]
+		if result.err != nil {
+			log.Printf("Failed to refresh account %q: %v", acct.Name, result.err)
+			if firstErr == nil {
+				firstErr = result.err
+			}
 			continue
 		}
-		e.deviceStates[device.DeviceID] = state
+		devices[acct.Name] = result.devices
+		deviceStates[acct.Name] = result.states
 	}
 
+	if len(devices) == 0 && firstErr != nil {
+		return firstErr
+	}
+
+	e.devices = devices
+	e.deviceStates = deviceStates
 	return nil
 }
+
+// refreshAccount refreshes the device inventory and state for a single
+// account's client, using its own MQTT source (if any) and REST poll pool.
+func (e *YoLinkExporter) refreshAccount(acct AccountClient) ([]Device, map[string]*DeviceStateResponse, error) {
+	sourceMode := viper.GetString("source")
+
+	timeout := time.Duration(viper.GetInt("scrape.timeout")) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// MQTT (and hybrid, which leans on MQTT for state) streams updates for
+	// devices we already know about, so the inventory only needs a REST
+	// fetch once; poll mode has no other way to learn about devices, so it
+	// refreshes the inventory every scrape.
+	devices := e.devices[acct.Name]
+	if sourceMode == "poll" || len(devices) == 0 {
+		fetched, err := acct.Client.GetDevices(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Only keep devices whose type has an enabled collector.
+		var enabled []Device
+		for _, device := range fetched {
+			if _, ok := e.collectors[device.Type]; ok {
+				enabled = append(enabled, device)
+			}
+		}
+		devices = enabled
+	}
+
+	deviceStates := make(map[string]*DeviceStateResponse)
+	maxAge := time.Duration(viper.GetInt("scrape.interval")) * time.Second
+
+	var toPoll []Device
+	for _, device := range devices {
+		if sourceMode != "poll" && acct.MQTTSource != nil {
+			if state, ok := acct.MQTTSource.StateFor(device.DeviceID, maxAge); ok {
+				deviceStates[device.DeviceID] = state
+				continue
+			}
+			if sourceMode == "mqtt" {
+				// No fresh MQTT report yet; leave it unpopulated rather than
+				// falling back to REST.
+				continue
+			}
+		}
+		toPoll = append(toPoll, device)
+	}
+
+	for deviceID, state := range pollDevices(ctx, acct.Client, acct.Name, toPoll) {
+		deviceStates[deviceID] = state
+	}
+
+	return devices, deviceStates, nil
+}
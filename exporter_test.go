@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRefreshDataToleratesOneAccountFailing(t *testing.T) {
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/open/yolink/token" {
+			json.NewEncoder(w).Encode(TokenResponse{AccessToken: "token", ExpiresIn: 3600})
+			return
+		}
+		resp := DeviceListResponse{Code: "000000"}
+		resp.Data.Devices = []Device{{DeviceID: "dev-1", Name: "Good Sensor", Type: "THSensor", ModelName: "YS8007"}}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer good.Close()
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer bad.Close()
+
+	exporter := NewYoLinkExporter([]AccountClient{
+		{Name: "good", Client: NewYoLinkClient("good", "key", "secret", good.URL)},
+		{Name: "bad", Client: NewYoLinkClient("bad", "key", "secret", bad.URL)},
+	})
+
+	if err := exporter.refreshData(); err != nil {
+		t.Fatalf("expected refreshData to succeed when only one account fails: %v", err)
+	}
+
+	if len(exporter.devices["good"]) != 1 {
+		t.Errorf("expected 1 device cached for the good account, got %d", len(exporter.devices["good"]))
+	}
+	if _, ok := exporter.devices["bad"]; ok {
+		t.Error("expected no devices cached for the failing account")
+	}
+}
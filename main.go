@@ -26,7 +26,7 @@ func main() {
 	rootCmd := &cobra.Command{
 		Use:   "yolink-exporter",
 		Short: "Prometheus exporter for YoLink thermometer/hygrometer devices",
-		Long:  `A Prometheus exporter that fetches data from YoLink API and exposes metrics for temperature, humidity, and battery levels.`,
+		Long:  `A Prometheus exporter that fetches data from YoLink API and exposes metrics for THSensor, LeakSensor, MotionSensor, DoorSensor, Outlet, and Hub devices.`,
 		RunE:  run,
 	}
 
@@ -46,29 +46,50 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Get API credentials from flags, environment, or config
-	apiKey := getAPIKey()
-	secret := getSecret()
-
-	if apiKey == "" || secret == "" {
-		return fmt.Errorf("API key and secret are required. Use --api-key and --secret flags, or set YOLINK_API_KEY and YOLINK_SECRET environment variables")
+	// Load the configured YoLink accounts (or fall back to the single
+	// api.key/api.secret account for backward compatibility)
+	accountClients, err := buildAccountClients()
+	if err != nil {
+		return err
 	}
 
-	// Create YoLink client
-	client := NewYoLinkClient(apiKey, secret, viper.GetString("api.endpoint"))
-
 	// Create exporter
-	exporter := NewYoLinkExporter(client)
+	exporter := NewYoLinkExporter(accountClients)
 
 	// Register metrics
 	prometheus.MustRegister(exporter)
 
+	// Watch the config file (if any) for live reloads, and expose the
+	// Prometheus-convention /-/reload endpoint for orchestration systems
+	// that prefer an explicit trigger over a file watch.
+	reloader := newReloader(exporter)
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		if err := reloader.watchConfig(configFile); err != nil {
+			log.Printf("Failed to watch config file for live reload: %v", err)
+		}
+	} else {
+		log.Println("No config file in use; live reload is only available via POST /-/reload")
+	}
+
 	// Setup HTTP server
 	http.Handle("/metrics", promhttp.Handler())
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
+	http.HandleFunc("/-/reload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := reloader.reload(); err != nil {
+			log.Printf("Config reload failed: %v", err)
+			http.Error(w, fmt.Sprintf("reload failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("reloaded\n"))
+	})
 
 	// Start server
 	addr := fmt.Sprintf("%s:%d", viper.GetString("server.host"), viper.GetInt("server.port"))
@@ -127,6 +148,93 @@ func getSecret() string {
 	return viper.GetString("api.secret")
 }
 
+// Account is one configured YoLink account/home. Key/Secret can come
+// straight from config, or Secret can be pulled from an environment
+// variable at load time via SecretFromEnv (the same idea as dex's
+// hash_from_env-style config knobs, for keeping credentials out of the
+// config file).
+type Account struct {
+	Name          string `mapstructure:"name"`
+	Key           string `mapstructure:"key"`
+	Secret        string `mapstructure:"secret"`
+	SecretFromEnv string `mapstructure:"secret_from_env"`
+	Endpoint      string `mapstructure:"endpoint"`
+}
+
+// loadAccounts returns the configured `accounts` list, or a single
+// "default" account built from api.key/api.secret/api.endpoint (plus the
+// --api-key/--secret flags and YOLINK_API_KEY/YOLINK_SECRET env vars) when
+// no accounts are configured.
+func loadAccounts() ([]Account, error) {
+	var accounts []Account
+	if err := viper.UnmarshalKey("accounts", &accounts); err != nil {
+		return nil, fmt.Errorf("failed to parse accounts: %w", err)
+	}
+
+	if len(accounts) == 0 {
+		accounts = []Account{{
+			Name:     "default",
+			Key:      getAPIKey(),
+			Secret:   getSecret(),
+			Endpoint: viper.GetString("api.endpoint"),
+		}}
+	}
+
+	for i := range accounts {
+		if accounts[i].Endpoint == "" {
+			accounts[i].Endpoint = viper.GetString("api.endpoint")
+		}
+		if accounts[i].SecretFromEnv != "" {
+			secret := os.Getenv(accounts[i].SecretFromEnv)
+			if secret == "" {
+				return nil, fmt.Errorf("account %q: env var %s is not set", accounts[i].Name, accounts[i].SecretFromEnv)
+			}
+			accounts[i].Secret = secret
+		}
+		if accounts[i].Key == "" || accounts[i].Secret == "" {
+			return nil, fmt.Errorf("account %q: key and secret are required. Use --api-key/--secret, YOLINK_API_KEY/YOLINK_SECRET, or the accounts config", accounts[i].Name)
+		}
+	}
+
+	return accounts, nil
+}
+
+// buildAccountClients loads the configured accounts and builds a
+// YoLinkClient (and MQTT source, if enabled) for each one. Used both at
+// startup and on every config reload.
+func buildAccountClients() ([]AccountClient, error) {
+	accounts, err := loadAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load accounts: %w", err)
+	}
+
+	sourceMode := viper.GetString("source")
+
+	var accountClients []AccountClient
+	for _, acct := range accounts {
+		client := NewYoLinkClient(acct.Name, acct.Key, acct.Secret, acct.Endpoint)
+
+		var mqttSource *MQTTSource
+		if sourceMode == "mqtt" || sourceMode == "hybrid" {
+			ms, err := NewMQTTSource(client)
+			if err != nil {
+				closeAccountClients(accountClients)
+				return nil, fmt.Errorf("account %q: failed to set up MQTT source: %w", acct.Name, err)
+			}
+			if err := ms.Start(); err != nil {
+				ms.Stop()
+				closeAccountClients(accountClients)
+				return nil, fmt.Errorf("account %q: failed to start MQTT source: %w", acct.Name, err)
+			}
+			mqttSource = ms
+		}
+
+		accountClients = append(accountClients, AccountClient{Name: acct.Name, Client: client, MQTTSource: mqttSource})
+	}
+
+	return accountClients, nil
+}
+
 func loadConfig() error {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -141,6 +249,10 @@ func loadConfig() error {
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("api.endpoint", "https://api.yosmart.com")
 	viper.SetDefault("scrape.interval", 60)
+	viper.SetDefault("scrape.concurrency", 8)
+	viper.SetDefault("scrape.timeout", 30)
+	viper.SetDefault("source", "poll") // mqtt | poll | hybrid
+	viper.SetDefault("collectors", []string{"thsensor"})
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Meta-metrics describing the exporter's own health, in the style of
+// mikrotik-exporter's scrape_collector_duration_seconds /
+// scrape_collector_success. These are registered against the default
+// registry via promauto so they show up on the existing /metrics handler
+// without any extra wiring.
+var (
+	scrapeDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "yolink_scrape_duration_seconds",
+		Help: "Time taken to fetch a device's state during the last scrape",
+	}, []string{"account", "device_id", "device_name"})
+
+	scrapeSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "yolink_scrape_success",
+		Help: "Whether the last scrape of a device succeeded (1) or failed (0)",
+	}, []string{"account", "device_id"})
+
+	apiRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "yolink_api_requests_total",
+		Help: "Total YoLink API requests by account, method, and response code",
+	}, []string{"account", "method", "code"})
+
+	apiRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "yolink_api_request_duration_seconds",
+		Help: "YoLink API request latency by account and method",
+	}, []string{"account", "method"})
+
+	tokenRefreshesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "yolink_token_refreshes_total",
+		Help: "Total YoLink OAuth token acquisitions/refreshes by account and result",
+	}, []string{"account", "result"})
+
+	configReloadsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "yolink_config_reloads_total",
+		Help: "Total config reload attempts by result",
+	}, []string{"result"})
+)
+
+// instrumentedTransport wraps an http.RoundTripper so that every call made
+// through a YoLinkClient's httpClient - token fetches, GetDevices,
+// GetDeviceState, GetHomeID - records yolink_api_requests_total and
+// yolink_api_request_duration_seconds, regardless of call path. This mirrors
+// promhttp.InstrumentRoundTripperDuration, except the "method" label comes
+// from the YoLink API method embedded in the request body rather than the
+// URL, since every v2/api call shares the same path.
+type instrumentedTransport struct {
+	next    http.RoundTripper
+	account string
+}
+
+func newInstrumentedTransport(account string) http.RoundTripper {
+	return &instrumentedTransport{next: http.DefaultTransport, account: account}
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := apiMethodForRequest(req)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	apiRequestDuration.WithLabelValues(t.account, method).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	apiRequestsTotal.WithLabelValues(t.account, method, code).Inc()
+
+	return resp, err
+}
+
+// apiMethodForRequest identifies the call for labeling purposes: the OAuth
+// token endpoint is its own "method", and every other call is a YoLink API
+// method name (e.g. "Home.getDeviceList") read from the JSON request body.
+func apiMethodForRequest(req *http.Request) string {
+	if req.URL.Path == "/open/yolink/token" {
+		return "oauth.token"
+	}
+	if req.Body == nil {
+		return "unknown"
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "unknown"
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	var parsed struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil || parsed.Method == "" {
+		return "unknown"
+	}
+	return parsed.Method
+}
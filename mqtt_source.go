@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/viper"
+)
+
+// MQTTSource subscribes to YoLink's MQTT broker and keeps an in-memory cache
+// of the latest reported state for each device, so Collect can read true
+// event time instead of triggering a REST poll on every scrape.
+type MQTTSource struct {
+	client *YoLinkClient
+	mqtt   mqtt.Client
+	homeID string
+
+	mutex      sync.RWMutex
+	states     map[string]*DeviceStateResponse
+	lastReport map[string]time.Time
+}
+
+// MQTTEvent is the envelope YoLink publishes to yl-home/<homeId>/<deviceId>/report.
+type MQTTEvent struct {
+	Event    string `json:"event"`
+	Time     int64  `json:"time"`
+	DeviceID string `json:"deviceId"`
+	Data     struct {
+		Online   bool   `json:"online"`
+		ReportAt string `json:"reportAt"`
+		State    struct {
+			Battery     int     `json:"battery"`
+			Humidity    float64 `json:"humidity"`
+			Temperature float64 `json:"temperature"`
+			State       string  `json:"state"`
+		} `json:"state"`
+		LoRaInfo struct {
+			Signal int `json:"signal"`
+		} `json:"loRaInfo"`
+	} `json:"data"`
+}
+
+// NewMQTTSource fetches the home ID token and prepares an MQTT client for it.
+// Call Start to connect and subscribe.
+func NewMQTTSource(client *YoLinkClient) (*MQTTSource, error) {
+	homeID, err := client.GetHomeID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home id: %w", err)
+	}
+
+	broker := viper.GetString("mqtt.broker")
+	if broker == "" {
+		broker = defaultMQTTBroker(client.endpoint)
+	}
+
+	src := &MQTTSource{
+		client:     client,
+		homeID:     homeID,
+		states:     make(map[string]*DeviceStateResponse),
+		lastReport: make(map[string]time.Time),
+	}
+
+	opts := mqtt.NewClientOptions()
+	opts.AddBroker(broker)
+	opts.SetClientID(fmt.Sprintf("yolink-exporter-%s", homeID))
+	opts.SetUsername(client.accessToken)
+	opts.SetAutoReconnect(true)
+	opts.SetDefaultPublishHandler(src.handleMessage)
+
+	src.mqtt = mqtt.NewClient(opts)
+
+	return src, nil
+}
+
+// Stop disconnects from the broker. Call it before discarding an
+// MQTTSource (e.g. on config reload) so its connection and background
+// goroutines don't leak.
+func (s *MQTTSource) Stop() {
+	if s.mqtt == nil || !s.mqtt.IsConnected() {
+		return
+	}
+	s.mqtt.Disconnect(250)
+}
+
+// Start connects to the broker and subscribes to this home's report topic.
+func (s *MQTTSource) Start() error {
+	if token := s.mqtt.Connect(); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("failed to connect to mqtt broker: %w", token.Error())
+	}
+
+	topic := fmt.Sprintf("yl-home/%s/+/report", s.homeID)
+	if token := s.mqtt.Subscribe(topic, 1, s.handleMessage); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, token.Error())
+	}
+
+	log.Printf("Subscribed to MQTT topic %s", topic)
+	return nil
+}
+
+func (s *MQTTSource) handleMessage(_ mqtt.Client, msg mqtt.Message) {
+	var event MQTTEvent
+	if err := json.Unmarshal(msg.Payload(), &event); err != nil {
+		log.Printf("Failed to decode MQTT event on %s: %v", msg.Topic(), err)
+		return
+	}
+
+	deviceID := event.DeviceID
+	if deviceID == "" {
+		if parts := strings.Split(msg.Topic(), "/"); len(parts) >= 3 {
+			deviceID = parts[2]
+		}
+	}
+	if deviceID == "" {
+		return
+	}
+
+	reportAt := event.Data.ReportAt
+	if reportAt == "" {
+		reportAt = time.Unix(event.Time/1000, 0).UTC().Format(time.RFC3339)
+	}
+
+	state := &DeviceStateResponse{}
+	state.Data.Online = event.Data.Online
+	state.Data.State = event.Data.State
+	state.Data.LoRaInfo = event.Data.LoRaInfo
+	state.Data.DeviceID = deviceID
+	state.Data.ReportAt = reportAt
+
+	s.mutex.Lock()
+	s.states[deviceID] = state
+	s.lastReport[deviceID] = time.Now()
+	s.mutex.Unlock()
+}
+
+// StateFor returns the most recent MQTT-reported state for a device and
+// whether it reported within maxAge.
+func (s *MQTTSource) StateFor(deviceID string, maxAge time.Duration) (*DeviceStateResponse, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	state, ok := s.states[deviceID]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(s.lastReport[deviceID]) > maxAge {
+		return state, false
+	}
+	return state, true
+}
+
+func defaultMQTTBroker(endpoint string) string {
+	host := strings.TrimPrefix(endpoint, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	return fmt.Sprintf("ssl://%s:8003", host)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// fakeMQTTMessage is a minimal mqtt.Message for feeding a payload through
+// handleMessage without a real broker.
+type fakeMQTTMessage struct {
+	topic   string
+	payload []byte
+}
+
+func (m *fakeMQTTMessage) Duplicate() bool   { return false }
+func (m *fakeMQTTMessage) Qos() byte         { return 0 }
+func (m *fakeMQTTMessage) Retained() bool    { return false }
+func (m *fakeMQTTMessage) Topic() string     { return m.topic }
+func (m *fakeMQTTMessage) MessageID() uint16 { return 0 }
+func (m *fakeMQTTMessage) Payload() []byte   { return m.payload }
+func (m *fakeMQTTMessage) Ack()              {}
+
+func TestHandleMessage(t *testing.T) {
+	src := &MQTTSource{
+		states:     make(map[string]*DeviceStateResponse),
+		lastReport: make(map[string]time.Time),
+	}
+
+	payload := `{
+		"deviceId": "dev-1",
+		"data": {
+			"online": true,
+			"reportAt": "2026-01-02T03:04:05Z",
+			"state": {"state": "alert", "battery": 3},
+			"loRaInfo": {"signal": -72}
+		}
+	}`
+
+	src.handleMessage(nil, &fakeMQTTMessage{topic: "yl-home/home-1/dev-1/report", payload: []byte(payload)})
+
+	state, ok := src.StateFor("dev-1", time.Minute)
+	if !ok {
+		t.Fatal("expected a cached state for dev-1")
+	}
+	if !state.Data.Online {
+		t.Error("expected Online to be true")
+	}
+	if state.Data.State.State != "alert" {
+		t.Errorf("expected State.State = %q, got %q", "alert", state.Data.State.State)
+	}
+	if state.Data.State.Battery != 3 {
+		t.Errorf("expected State.Battery = 3, got %d", state.Data.State.Battery)
+	}
+	if state.Data.LoRaInfo.Signal != -72 {
+		t.Errorf("expected LoRaInfo.Signal = -72, got %d", state.Data.LoRaInfo.Signal)
+	}
+	if state.Data.ReportAt != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected ReportAt = %q, got %q", "2026-01-02T03:04:05Z", state.Data.ReportAt)
+	}
+	if state.Data.DeviceID != "dev-1" {
+		t.Errorf("expected DeviceID = %q, got %q", "dev-1", state.Data.DeviceID)
+	}
+}
+
+func TestHandleMessageFallsBackToTopicDeviceIDAndEpochTime(t *testing.T) {
+	src := &MQTTSource{
+		states:     make(map[string]*DeviceStateResponse),
+		lastReport: make(map[string]time.Time),
+	}
+
+	payload := `{"time": 1735689600000, "data": {"online": false, "state": {"state": "normal"}}}`
+
+	src.handleMessage(nil, &fakeMQTTMessage{topic: "yl-home/home-1/dev-2/report", payload: []byte(payload)})
+
+	state, ok := src.StateFor("dev-2", time.Minute)
+	if !ok {
+		t.Fatal("expected a cached state keyed by the topic-derived device ID")
+	}
+	want := time.Unix(1735689600, 0).UTC().Format(time.RFC3339)
+	if state.Data.ReportAt != want {
+		t.Errorf("expected ReportAt = %q, got %q", want, state.Data.ReportAt)
+	}
+}
+
+var _ mqtt.Message = (*fakeMQTTMessage)(nil)
+
+func TestMQTTSourceStateForMaxAge(t *testing.T) {
+	src := &MQTTSource{
+		states:     make(map[string]*DeviceStateResponse),
+		lastReport: make(map[string]time.Time),
+	}
+
+	if _, ok := src.StateFor("unknown-device", time.Minute); ok {
+		t.Error("expected StateFor to report no state for a device that never reported")
+	}
+
+	src.states["dev-1"] = &DeviceStateResponse{}
+	src.lastReport["dev-1"] = time.Now()
+	if _, ok := src.StateFor("dev-1", time.Minute); !ok {
+		t.Error("expected StateFor to report fresh state within maxAge")
+	}
+
+	src.lastReport["dev-1"] = time.Now().Add(-2 * time.Minute)
+	if _, ok := src.StateFor("dev-1", time.Minute); ok {
+		t.Error("expected StateFor to reject state older than maxAge")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// reloader rebuilds the exporter's accounts and collectors from the live
+// viper config. It's driven by both the fsnotify watcher and the
+// POST /-/reload endpoint, and serializes the two so they can't race.
+type reloader struct {
+	mutex    sync.Mutex
+	exporter *YoLinkExporter
+}
+
+func newReloader(exporter *YoLinkExporter) *reloader {
+	return &reloader{exporter: exporter}
+}
+
+// watchConfig mirrors statsd_exporter's watchConfig: it watches the config
+// file's *directory* rather than the file itself, and filters events down
+// to that file by name. Watching the file directly would miss the most
+// common way configs get edited in practice - vim's default backup/
+// writebackup save, a ConfigMap remount, or any other create-temp-then-
+// rename writer - since that replaces the inode fsnotify is watching
+// instead of writing through it. When that happens we re-add the watch so
+// the next write or create is still seen.
+func (r *reloader) watchConfig(configFile string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	configFile = filepath.Clean(configFile)
+	configDir := filepath.Dir(configFile)
+
+	if err := watcher.Add(configDir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch config directory %s: %w", configDir, err)
+	}
+
+	go func() {
+		for event := range watcher.Events {
+			if event.Name != configFile {
+				continue
+			}
+
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				if err := watcher.Add(configDir); err != nil {
+					log.Printf("Failed to re-add config watch after %s was replaced: %v", configFile, err)
+				}
+				continue
+			}
+
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			log.Printf("Config file %s changed, reloading", event.Name)
+			if err := r.reload(); err != nil {
+				log.Printf("Failed to reload config: %v", err)
+			}
+		}
+	}()
+
+	go func() {
+		for err := range watcher.Errors {
+			log.Printf("Config watcher error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// reload re-reads the config file, rebuilds the account clients, and
+// swaps them into the running exporter. On any failure it counts it and
+// returns the error for the caller to log, and the exporter keeps its
+// running accounts and collectors. If the file could not be re-read, viper
+// keeps its previous values too; if it was re-read but the accounts could
+// not be rebuilt, viper holds the new values, so settings read on each
+// scrape (scrape.interval, scrape.timeout, scrape.concurrency, source)
+// take effect.
+func (r *reloader) reload() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := viper.ReadInConfig(); err != nil {
+		configReloadsTotal.WithLabelValues("failure").Inc()
+		return fmt.Errorf("failed to re-read config: %w", err)
+	}
+
+	accountClients, err := buildAccountClients()
+	if err != nil {
+		configReloadsTotal.WithLabelValues("failure").Inc()
+		return fmt.Errorf("failed to rebuild accounts: %w", err)
+	}
+
+	r.exporter.Reconfigure(accountClients)
+	configReloadsTotal.WithLabelValues("success").Inc()
+	log.Println("Config reloaded successfully")
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestReloadLeavesRunningConfigOnError(t *testing.T) {
+	viper.Reset()
+	viper.SetConfigFile("/nonexistent/yolink-exporter-config.yaml")
+
+	exporter := NewYoLinkExporter([]AccountClient{
+		{Name: "original", Client: NewYoLinkClient("original", "key", "secret", "https://api.yosmart.com")},
+	})
+	r := newReloader(exporter)
+
+	if err := r.reload(); err == nil {
+		t.Fatal("expected reload to fail when the config file can't be read")
+	}
+
+	if len(exporter.accounts) != 1 || exporter.accounts[0].Name != "original" {
+		t.Errorf("expected the running config to be left in place, got %+v", exporter.accounts)
+	}
+}
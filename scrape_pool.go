@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+// yoLinkRateLimit is YoLink's documented token bucket for the Open API: 6
+// requests per second.
+const yoLinkRateLimit = 6
+
+const maxPollAttempts = 3
+
+// pollDevices fans a REST state fetch for each device out across a bounded
+// worker pool, shaped like Prometheus' StorageQueueManager.runShard: workers
+// pull jobs from a shared channel, share a rate limiter, and retry with
+// backoff before giving up on a device. The whole pool is bounded by ctx so
+// a hung device is dropped instead of blocking the scrape. Each account
+// polls through its own client and rate limiter since YoLink's token
+// bucket is per-account.
+func pollDevices(ctx context.Context, client *YoLinkClient, account string, devices []Device) map[string]*DeviceStateResponse {
+	concurrency := viper.GetInt("scrape.concurrency")
+	if concurrency <= 0 {
+		concurrency = 8
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(yoLinkRateLimit), yoLinkRateLimit)
+
+	jobs := make(chan Device)
+	results := make(map[string]*DeviceStateResponse)
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for device := range jobs {
+				state, err := pollDeviceWithRetry(ctx, client, limiter, account, device)
+				if err != nil {
+					log.Printf("Failed to get state for device %s (%s, account %s): %v", device.Name, device.DeviceID, account, err)
+					continue
+				}
+				resultsMu.Lock()
+				results[device.DeviceID] = state
+				resultsMu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, device := range devices {
+		select {
+		case jobs <- device:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+
+	wg.Wait()
+	return results
+}
+
+// pollDeviceWithRetry fetches one device's state, making up to
+// maxPollAttempts attempts with exponential backoff between them before
+// giving up. It records
+// yolink_scrape_duration_seconds/yolink_scrape_success for the device
+// covering all attempts.
+func pollDeviceWithRetry(ctx context.Context, client *YoLinkClient, limiter *rate.Limiter, account string, device Device) (*DeviceStateResponse, error) {
+	start := time.Now()
+	state, err := pollDeviceAttempts(ctx, client, limiter, device)
+
+	scrapeDuration.WithLabelValues(account, device.DeviceID, device.Name).Set(time.Since(start).Seconds())
+	success := 0.0
+	if err == nil {
+		success = 1.0
+	}
+	scrapeSuccess.WithLabelValues(account, device.DeviceID).Set(success)
+
+	return state, err
+}
+
+func pollDeviceAttempts(ctx context.Context, client *YoLinkClient, limiter *rate.Limiter, device Device) (*DeviceStateResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxPollAttempts; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		state, err := client.GetDeviceState(ctx, device)
+		if err == nil {
+			return state, nil
+		}
+		lastErr = err
+
+		select {
+		case <-time.After(pollBackoff(attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return nil, lastErr
+}
+
+func pollBackoff(attempt int) time.Duration {
+	return (200 * time.Millisecond) << attempt
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestPollBackoffGrowsExponentially(t *testing.T) {
+	prev := pollBackoff(0)
+	for attempt := 1; attempt < 4; attempt++ {
+		next := pollBackoff(attempt)
+		if next <= prev {
+			t.Errorf("pollBackoff(%d) = %v, want more than pollBackoff(%d) = %v", attempt, next, attempt-1, prev)
+		}
+		prev = next
+	}
+}
+
+func TestPollDeviceAttemptsExhaustsRetries(t *testing.T) {
+	var deviceCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/open/yolink/token" {
+			json.NewEncoder(w).Encode(TokenResponse{AccessToken: "token", ExpiresIn: 3600})
+			return
+		}
+		deviceCalls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewYoLinkClient("test-account", "test-key", "test-secret", server.URL)
+	limiter := rate.NewLimiter(rate.Limit(1000), 1000)
+	device := Device{DeviceID: "dev-1", Type: "THSensor"}
+
+	if _, err := pollDeviceAttempts(context.Background(), client, limiter, device); err == nil {
+		t.Fatal("expected pollDeviceAttempts to return an error after exhausting retries")
+	}
+
+	if deviceCalls != maxPollAttempts {
+		t.Errorf("expected %d device state attempts, got %d", maxPollAttempts, deviceCalls)
+	}
+}
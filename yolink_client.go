@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 )
 
 type YoLinkClient struct {
+	account      string
 	apiKey       string
 	secret       string
 	endpoint     string
@@ -57,8 +59,14 @@ type DeviceStateResponse struct {
 			Battery     int     `json:"battery"`
 			Humidity    float64 `json:"humidity"`
 			Temperature float64 `json:"temperature"`
-			State       string  `json:"state"`
+			// State carries the device-specific reading: THSensor doesn't
+			// use it, LeakSensor/MotionSensor report "alert"/"normal", and
+			// DoorSensor/Outlet report "open"/"closed".
+			State string `json:"state"`
 		} `json:"state"`
+		LoRaInfo struct {
+			Signal int `json:"signal"`
+		} `json:"loRaInfo"`
 		DeviceID string `json:"deviceId"`
 		ReportAt string `json:"reportAt"`
 	} `json:"data"`
@@ -71,12 +79,19 @@ type APIRequest struct {
 	Token        string `json:"token,omitempty"`
 }
 
-func NewYoLinkClient(apiKey, secret, endpoint string) *YoLinkClient {
+// NewYoLinkClient builds a client for one YoLink account. account is used
+// purely as a metrics label so multiple accounts' requests can be told
+// apart on /metrics.
+func NewYoLinkClient(account, apiKey, secret, endpoint string) *YoLinkClient {
 	return &YoLinkClient{
-		apiKey:     apiKey,
-		secret:     secret,
-		endpoint:   endpoint,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		account:  account,
+		apiKey:   apiKey,
+		secret:   secret,
+		endpoint: endpoint,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: newInstrumentedTransport(account),
+		},
 	}
 }
 
@@ -90,7 +105,15 @@ func (c *YoLinkClient) ensureValidToken() error {
 	return nil
 }
 
-func (c *YoLinkClient) getInitialToken() error {
+func (c *YoLinkClient) getInitialToken() (err error) {
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+		tokenRefreshesTotal.WithLabelValues(c.account, result).Inc()
+	}()
+
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 	data.Set("client_id", c.apiKey)
@@ -131,7 +154,15 @@ func (c *YoLinkClient) getInitialToken() error {
 	return nil
 }
 
-func (c *YoLinkClient) refreshAccessToken() error {
+func (c *YoLinkClient) refreshAccessToken() (err error) {
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+		tokenRefreshesTotal.WithLabelValues(c.account, result).Inc()
+	}()
+
 	data := url.Values{}
 	data.Set("grant_type", "refresh_token")
 	data.Set("client_id", c.apiKey)
@@ -172,7 +203,7 @@ func (c *YoLinkClient) refreshAccessToken() error {
 	return nil
 }
 
-func (c *YoLinkClient) GetDevices() ([]Device, error) {
+func (c *YoLinkClient) GetDevices(ctx context.Context) ([]Device, error) {
 	if err := c.ensureValidToken(); err != nil {
 		return nil, fmt.Errorf("failed to ensure valid token: %w", err)
 	}
@@ -187,7 +218,7 @@ func (c *YoLinkClient) GetDevices() ([]Device, error) {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.endpoint+"/open/yolink/v2/api", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/open/yolink/v2/api", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -220,24 +251,77 @@ func (c *YoLinkClient) GetDevices() ([]Device, error) {
 		return nil, fmt.Errorf("API returned error code: %s", deviceResp.Code)
 	}
 
-	// Filter for THSensor devices only
-	var thSensors []Device
-	for _, device := range deviceResp.Data.Devices {
-		if device.Type == "THSensor" && device.ModelName == "YS8007-UC" {
-			thSensors = append(thSensors, device)
-		}
+	return deviceResp.Data.Devices, nil
+}
+
+type HomeInfoResponse struct {
+	Code string `json:"code"`
+	Time int64  `json:"time"`
+	Data struct {
+		HomeID string `json:"id"`
+	} `json:"data"`
+}
+
+// GetHomeID fetches the home ID token used to build the MQTT report topic
+// (yl-home/<homeId>/+/report).
+func (c *YoLinkClient) GetHomeID() (string, error) {
+	if err := c.ensureValidToken(); err != nil {
+		return "", fmt.Errorf("failed to ensure valid token: %w", err)
+	}
+
+	reqBody := APIRequest{
+		Method: "Home.getGeneralInfo",
+		Time:   time.Now().Unix(),
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.endpoint+"/open/yolink/v2/api", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "yolink-exporter/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get home info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("home info request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var homeResp HomeInfoResponse
+	if err := json.Unmarshal(body, &homeResp); err != nil {
+		return "", fmt.Errorf("failed to parse home info response: %w", err)
 	}
 
-	return thSensors, nil
+	if homeResp.Code != "000000" {
+		return "", fmt.Errorf("API returned error code: %s", homeResp.Code)
+	}
+
+	return homeResp.Data.HomeID, nil
 }
 
-func (c *YoLinkClient) GetDeviceState(device Device) (*DeviceStateResponse, error) {
+func (c *YoLinkClient) GetDeviceState(ctx context.Context, device Device) (*DeviceStateResponse, error) {
 	if err := c.ensureValidToken(); err != nil {
 		return nil, fmt.Errorf("failed to ensure valid token: %w", err)
 	}
 
 	reqBody := APIRequest{
-		Method:       "THSensor.getState",
+		Method:       fmt.Sprintf("%s.getState", device.Type),
 		Time:         time.Now().Unix(),
 		TargetDevice: device.DeviceID,
 		Token:        device.Token,
@@ -248,7 +332,7 @@ func (c *YoLinkClient) GetDeviceState(device Device) (*DeviceStateResponse, erro
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.endpoint+"/open/yolink/v2/api", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+"/open/yolink/v2/api", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
 
 func TestNewYoLinkClient(t *testing.T) {
-	client := NewYoLinkClient("test-key", "test-secret", "https://api.yosmart.com")
+	client := NewYoLinkClient("test-account", "test-key", "test-secret", "https://api.yosmart.com")
+
+	if client.account != "test-account" {
+		t.Errorf("Expected account to be 'test-account', got '%s'", client.account)
+	}
 
 	if client.apiKey != "test-key" {
 		t.Errorf("Expected apiKey to be 'test-key', got '%s'", client.apiKey)
@@ -26,7 +34,7 @@ func TestNewYoLinkClient(t *testing.T) {
 }
 
 func TestTokenExpiry(t *testing.T) {
-	client := NewYoLinkClient("test-key", "test-secret", "https://api.yosmart.com")
+	client := NewYoLinkClient("test-account", "test-key", "test-secret", "https://api.yosmart.com")
 
 	// Test with no token
 	if !client.tokenExpiry.IsZero() {
@@ -45,45 +53,42 @@ func TestTokenExpiry(t *testing.T) {
 	}
 }
 
-func TestDeviceFiltering(t *testing.T) {
-	devices := []Device{
-		{
-			DeviceID:  "test1",
-			Name:      "Test Sensor 1",
-			Type:      "THSensor",
-			ModelName: "YS8007-UC",
-		},
-		{
-			DeviceID:  "test2",
-			Name:      "Test Hub",
-			Type:      "Hub",
-			ModelName: "YS1603-UC",
-		},
-		{
-			DeviceID:  "test3",
-			Name:      "Test Sensor 2",
-			Type:      "THSensor",
-			ModelName: "YS8007-UC",
-		},
-	}
-
-	// Simulate filtering logic
-	var thSensors []Device
-	for _, device := range devices {
-		if device.Type == "THSensor" && device.ModelName == "YS8007-UC" {
-			thSensors = append(thSensors, device)
-		}
+func TestGetDeviceStateSendsPerTypeMethod(t *testing.T) {
+	cases := []struct {
+		deviceType string
+		wantMethod string
+	}{
+		{"THSensor", "THSensor.getState"},
+		{"LeakSensor", "LeakSensor.getState"},
+		{"MotionSensor", "MotionSensor.getState"},
+		{"DoorSensor", "DoorSensor.getState"},
+		{"Outlet", "Outlet.getState"},
+		{"Hub", "Hub.getState"},
 	}
 
-	if len(thSensors) != 2 {
-		t.Errorf("Expected 2 THSensor devices, got %d", len(thSensors))
-	}
+	for _, tc := range cases {
+		t.Run(tc.deviceType, func(t *testing.T) {
+			var gotMethod string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/open/yolink/token" {
+					json.NewEncoder(w).Encode(TokenResponse{AccessToken: "token", ExpiresIn: 3600})
+					return
+				}
+				var req APIRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				gotMethod = req.Method
+				json.NewEncoder(w).Encode(DeviceStateResponse{Code: "000000"})
+			}))
+			defer server.Close()
 
-	if thSensors[0].DeviceID != "test1" {
-		t.Errorf("Expected first device to be 'test1', got '%s'", thSensors[0].DeviceID)
-	}
+			client := NewYoLinkClient("test-account", "test-key", "test-secret", server.URL)
+			if _, err := client.GetDeviceState(context.Background(), Device{DeviceID: "dev-1", Type: tc.deviceType}); err != nil {
+				t.Fatalf("GetDeviceState returned error: %v", err)
+			}
 
-	if thSensors[1].DeviceID != "test3" {
-		t.Errorf("Expected second device to be 'test3', got '%s'", thSensors[1].DeviceID)
+			if gotMethod != tc.wantMethod {
+				t.Errorf("method = %q, want %q", gotMethod, tc.wantMethod)
+			}
+		})
 	}
 }